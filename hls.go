@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChamikaSamaraweera/go-yt-radio-server/pkg/throttle"
+)
+
+// hlsSession tracks one live HLS transcode: its working directory, the
+// ffmpeg process writing segments into it, and when it was last touched.
+type hlsSession struct {
+	dir        string
+	ffmpegCmd  *exec.Cmd
+	mu         sync.Mutex
+	lastAccess time.Time
+}
+
+// hlsSessions maps session id -> *hlsSession for all in-flight HLS streams.
+var hlsSessions sync.Map
+
+// hlsSessionID derives a stable session key from the source URL so repeat
+// requests for the same video reuse the same transcode.
+func hlsSessionID(vidURL string) string {
+	sum := sha256.Sum256([]byte(vidURL))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// hlsStartHandler accepts ?url=<YouTube URL>, derives the session id from a
+// hash of the URL, lazily starts the ffmpeg HLS transcode for it, and
+// redirects the client to its playlist.
+func hlsStartHandler(ytDlpPath, ffmpegPath, cookiesPath string, throttlePool *throttle.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vidURL := r.URL.Query().Get("url")
+		if vidURL == "" || !isYouTubeURL(vidURL) {
+			http.Error(w, "Missing or invalid ?url=...", http.StatusBadRequest)
+			return
+		}
+
+		id := hlsSessionID(vidURL)
+		if _, ok := hlsSessions.Load(id); !ok {
+			sess, err := startHLSSession(ytDlpPath, ffmpegPath, cookiesPath, throttlePool, vidURL)
+			if err != nil {
+				log.Printf("❌ HLS session start failed: %v", err)
+				http.Error(w, "Failed to start HLS session", http.StatusInternalServerError)
+				return
+			}
+			if _, loaded := hlsSessions.LoadOrStore(id, sess); loaded {
+				// Another request raced us to create the session; drop ours.
+				sess.ffmpegCmd.Process.Kill()
+				os.RemoveAll(sess.dir)
+			} else {
+				log.Printf("📺 HLS session %s started for %s", id, vidURL)
+			}
+		}
+
+		http.Redirect(w, r, "/radio/hls/"+id+"/index.m3u8", http.StatusFound)
+	}
+}
+
+// hlsHandler serves /radio/hls/{id}/{file} — the playlist and segment files
+// of an already-started session, straight off disk.
+func hlsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rest := strings.TrimPrefix(r.URL.Path, "/radio/hls/")
+		parts := strings.SplitN(rest, "/", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			http.Error(w, "expected /radio/hls/{id}/{file}", http.StatusBadRequest)
+			return
+		}
+		id, file := parts[0], parts[1]
+
+		value, ok := hlsSessions.Load(id)
+		if !ok {
+			http.Error(w, "Unknown or expired HLS session; restart via /radio/hls?url=...", http.StatusNotFound)
+			return
+		}
+
+		sess := value.(*hlsSession)
+		sess.mu.Lock()
+		sess.lastAccess = time.Now()
+		sess.mu.Unlock()
+
+		switch {
+		case strings.HasSuffix(file, ".m3u8"):
+			w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+		case strings.HasSuffix(file, ".ts"):
+			w.Header().Set("Content-Type", "video/mp2t")
+		}
+		http.ServeFile(w, r, filepath.Join(sess.dir, filepath.Base(file)))
+	}
+}
+
+// startHLSSession creates a per-session temp dir and launches the
+// yt-dlp → ffmpeg pipeline, writing a rolling playlist + segments into it.
+func startHLSSession(ytDlpPath, ffmpegPath, cookiesPath string, throttlePool *throttle.Pool, vidURL string) (*hlsSession, error) {
+	dir, err := os.MkdirTemp("", "radio-hls-*")
+	if err != nil {
+		return nil, fmt.Errorf("mkdir temp: %w", err)
+	}
+
+	ytdlpArgs := []string{"-f", "bestaudio[ext=m4a]/bestaudio", "-o", "-", "--no-warnings", "--quiet"}
+	if cookiesPath != "" {
+		ytdlpArgs = append(ytdlpArgs, "--cookies", cookiesPath)
+	}
+	ytdlpArgs = append(ytdlpArgs, vidURL)
+
+	ffmpegCmd := exec.Command(ffmpegPath,
+		"-hide_banner", "-loglevel", "error",
+		"-i", "pipe:0",
+		"-vn",
+		"-f", "hls",
+		"-hls_time", "4",
+		"-hls_list_size", "6",
+		"-hls_flags", "delete_segments+append_list",
+		"-hls_segment_type", "mpegts",
+		"-hls_segment_filename", filepath.Join(dir, "segment_%d.ts"),
+		filepath.Join(dir, "index.m3u8"),
+	)
+
+	// Route through the source-IP/proxy pool when one is configured, same as
+	// the plain stream endpoint, so HLS sessions don't hit YouTube's rate
+	// limiter from a single unrotated address.
+	var (
+		ytdlpCmd    *exec.Cmd
+		ytdlpStdout io.ReadCloser
+	)
+	if throttlePool.Len() > 0 {
+		cmd, reader, entry, err := throttlePool.StreamWithRetry(context.Background(), ytDlpPath, ytdlpArgs)
+		if err != nil {
+			os.RemoveAll(dir)
+			return nil, fmt.Errorf("throttle pool exhausted: %w", err)
+		}
+		log.Printf("🔁 Using pool entry %s for HLS %s", entry.Value, vidURL)
+		ytdlpCmd, ytdlpStdout = cmd, reader
+	} else {
+		cmd := exec.Command(ytDlpPath, ytdlpArgs...)
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			os.RemoveAll(dir)
+			return nil, fmt.Errorf("yt-dlp pipe: %w", err)
+		}
+		if err := cmd.Start(); err != nil {
+			os.RemoveAll(dir)
+			return nil, fmt.Errorf("yt-dlp start: %w", err)
+		}
+		ytdlpCmd, ytdlpStdout = cmd, stdout
+	}
+	ffmpegCmd.Stdin = ytdlpStdout
+
+	if err := ffmpegCmd.Start(); err != nil {
+		ytdlpCmd.Process.Kill()
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("ffmpeg start: %w", err)
+	}
+
+	go func() {
+		ytdlpCmd.Wait()
+		ffmpegCmd.Wait()
+	}()
+
+	return &hlsSession{dir: dir, ffmpegCmd: ffmpegCmd, lastAccess: time.Now()}, nil
+}
+
+// gcHLSSessions periodically reaps sessions idle longer than idleTimeout,
+// killing their ffmpeg process and removing their temp directory.
+func gcHLSSessions(idleTimeout time.Duration) {
+	ticker := time.NewTicker(idleTimeout / 2)
+	defer ticker.Stop()
+	for range ticker.C {
+		hlsSessions.Range(func(key, value any) bool {
+			sess := value.(*hlsSession)
+			sess.mu.Lock()
+			idle := time.Since(sess.lastAccess)
+			sess.mu.Unlock()
+			if idle < idleTimeout {
+				return true
+			}
+			log.Printf("🧹 Reaping idle HLS session %v (idle %s)", key, idle.Round(time.Second))
+			if sess.ffmpegCmd.Process != nil {
+				sess.ffmpegCmd.Process.Kill()
+			}
+			os.RemoveAll(sess.dir)
+			hlsSessions.Delete(key)
+			return true
+		})
+	}
+}
@@ -2,29 +2,55 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"io"
 	"log"
+	"math"
 	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/ChamikaSamaraweera/go-yt-radio-server/pkg/cache"
+	"github.com/ChamikaSamaraweera/go-yt-radio-server/pkg/icy"
+	"github.com/ChamikaSamaraweera/go-yt-radio-server/pkg/streamer"
+	"github.com/ChamikaSamaraweera/go-yt-radio-server/pkg/throttle"
 	"github.com/joho/godotenv"
 )
 
-func getConfig() (host, port, ytDlpPath, ffmpegPath, cookiesPath string) {
+// icyTrackCache avoids re-running "yt-dlp -J" for a URL we've already
+// resolved title/uploader metadata for.
+var icyTrackCache = icy.NewCache(256)
+
+func getConfig() (host, port, ytDlpPath, ffmpegPath, cookiesPath, extractor string, hlsIdleTimeout, throttleCooldown time.Duration) {
 	_ = godotenv.Load()
 	host = getEnv("HOST", "")
 	port = getEnv("PORT", "8080")
 	ytDlpPath = getEnv("YT_DLP_PATH", "yt-dlp")
 	ffmpegPath = getEnv("FFMPEG_PATH", "ffmpeg")
 	cookiesPath = getEnv("COOKIES_PATH", "")
+	extractor = getEnv("EXTRACTOR", "ytdlp")
+	if extractor != "native" && extractor != "ytdlp" {
+		log.Printf("⚠️ Invalid EXTRACTOR='%s', using ytdlp", extractor)
+		extractor = "ytdlp"
+	}
 	if _, err := strconv.Atoi(port); err != nil {
 		log.Printf("⚠️ Invalid PORT='%s', using 8080", port)
 		port = "8080"
 	}
+	hlsIdleTimeout = 60 * time.Second
+	if secs, err := strconv.Atoi(getEnv("HLS_IDLE_TIMEOUT", "")); err == nil && secs > 0 {
+		hlsIdleTimeout = time.Duration(secs) * time.Second
+	}
+	throttleCooldown = 10 * time.Minute
+	if secs, err := strconv.Atoi(getEnv("THROTTLE_COOLDOWN", "")); err == nil && secs > 0 {
+		throttleCooldown = time.Duration(secs) * time.Second
+	}
 	return
 }
 
@@ -35,6 +61,56 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+// seekCBRBytesPerSecond is the byte rate implied by our fixed 128kbps CBR
+// encode target, used to translate Range byte offsets into time offsets.
+const seekCBRBytesPerSecond = 128000 / 8
+
+// probeDuration runs "yt-dlp -J --skip-download" once to learn a video's
+// duration, so a Range request can be answered with a correct Content-Length
+// and Content-Range before any audio is transcoded.
+func probeDuration(ytDlpPath, cookiesPath, vidURL string) (float64, error) {
+	args := []string{"-J", "--skip-download", "--no-warnings"}
+	if cookiesPath != "" {
+		args = append(args, "--cookies", cookiesPath)
+	}
+	args = append(args, vidURL)
+
+	out, err := exec.Command(ytDlpPath, args...).Output()
+	if err != nil {
+		return 0, fmt.Errorf("yt-dlp -J: %w", err)
+	}
+
+	var meta struct {
+		Duration float64 `json:"duration"`
+	}
+	if err := json.Unmarshal(out, &meta); err != nil {
+		return 0, fmt.Errorf("parse yt-dlp JSON: %w", err)
+	}
+	if meta.Duration <= 0 {
+		return 0, fmt.Errorf("no duration in yt-dlp metadata")
+	}
+	return meta.Duration, nil
+}
+
+// parseRangeStart extracts the start byte from a "Range: bytes=<start>-"
+// header. Only the first range in a multi-range request is honored.
+func parseRangeStart(header string) (int64, bool) {
+	const prefix = "bytes="
+	if !strings.HasPrefix(header, prefix) {
+		return 0, false
+	}
+	spec := strings.SplitN(strings.TrimPrefix(header, prefix), ",", 2)[0]
+	start := strings.SplitN(spec, "-", 2)[0]
+	if start == "" {
+		return 0, false
+	}
+	v, err := strconv.ParseInt(start, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
 func listFormats(ytDlpPath, cookiesPath, vidURL string) string {
 	args := []string{"--list-formats"}
 	if cookiesPath != "" {
@@ -50,11 +126,11 @@ func listFormats(ytDlpPath, cookiesPath, vidURL string) string {
 	return string(output)
 }
 
-func streamHandler(ytDlpPath, ffmpegPath, cookiesPath string) http.HandlerFunc {
+func streamHandler(ytDlpPath, ffmpegPath, cookiesPath, extractor string, throttlePool *throttle.Pool, cacheBackend cache.Backend) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vidURL := r.URL.Query().Get("url")
 		debug := r.URL.Query().Get("debug")
-		
+
 		if vidURL == "" {
 			http.Error(w, "Missing ?url=...", http.StatusBadRequest)
 			return
@@ -66,17 +142,123 @@ func streamHandler(ytDlpPath, ffmpegPath, cookiesPath string) http.HandlerFunc {
 
 		if debug == "1" || debug == "true" {
 			w.Header().Set("Content-Type", "text/plain")
-			formats := listFormats(ytDlpPath, cookiesPath, vidURL)
 			w.Write([]byte("=== Available Formats ===\n\n"))
-			w.Write([]byte(formats))
+			if extractor == "native" {
+				w.Write([]byte(streamer.FormatDebugString(r.Context(), vidURL)))
+			} else {
+				w.Write([]byte(listFormats(ytDlpPath, cookiesPath, vidURL)))
+			}
+			return
+		}
+
+		if extractor == "native" {
+			log.Printf("🔍 Starting native stream for: %s", vidURL)
+			if err := streamer.StreamFromURLNative(r.Context(), w, vidURL); err != nil {
+				log.Printf("❌ Native stream failed: %v", err)
+			}
 			return
 		}
 
+		// cacheKey is derived from the canonical video ID and our fixed
+		// encode settings (128kbps, 44.1kHz, stereo) so a cache hit serves
+		// the finished file via http.ServeContent, which handles Range/seek
+		// requests for free. Using the canonical ID (rather than the raw
+		// request URL) means youtu.be/X, youtube.com/watch?v=X, and
+		// .../watch?v=X&si=...&t=30s all hit the same cache entry.
+		cacheKey := cache.Key(canonicalVideoID(vidURL), 128, 44100, 2)
+		if cacheBackend != nil {
+			if reader, size, ok := cacheBackend.Get(cacheKey); ok {
+				defer reader.Close()
+				w.Header().Set("Content-Type", "audio/mpeg")
+				log.Printf("📦 cache hit for %s (%d bytes)", vidURL, size)
+
+				// ICY and Range/seek are mutually exclusive (see the
+				// seekMode check below for the live-transcode path), so a
+				// plain Icy-MetaData request is served whole through the
+				// ICY writer instead of ServeContent, which would otherwise
+				// drop the now-playing metadata on every cache hit.
+				wantsICY := r.Header.Get("Icy-MetaData") == "1"
+				wantsSeek := r.Header.Get("Range") != "" || r.URL.Query().Get("t") != ""
+				if wantsICY && !wantsSeek {
+					icyWriter := newICYWriter(w, ytDlpPath, cookiesPath, vidURL)
+					w.Header().Set("Accept-Ranges", "none")
+					w.WriteHeader(http.StatusOK)
+					io.Copy(icyWriter, reader)
+					return
+				}
+
+				http.ServeContent(w, r, "", time.Time{}, reader)
+				return
+			}
+		}
+
+		// A Range header or ?t=<seconds> puts us in seekable mode: probe the
+		// duration once so we can answer with 206 + correct Content-Length,
+		// then seek ffmpeg to the matching time offset.
+		var startSec float64
+		if tParam := r.URL.Query().Get("t"); tParam != "" {
+			if v, err := strconv.ParseFloat(tParam, 64); err == nil && v > 0 {
+				startSec = v
+			}
+		}
+
+		rangeHeader := r.Header.Get("Range")
+		var rangeStartByte int64
+		if rangeHeader != "" {
+			if start, ok := parseRangeStart(rangeHeader); ok {
+				rangeStartByte = start
+				startSec = float64(start) / seekCBRBytesPerSecond
+			}
+		}
+
+		seekMode := rangeHeader != "" || startSec > 0
+
 		w.Header().Set("Content-Type", "audio/mpeg")
 		w.Header().Set("Cache-Control", "no-cache")
 		w.Header().Set("Connection", "keep-alive")
-		w.Header().Set("Accept-Ranges", "none")
-		w.Header().Set("Transfer-Encoding", "chunked")
+
+		var contentLength int64
+		if seekMode {
+			duration, err := probeDuration(ytDlpPath, cookiesPath, vidURL)
+			if err != nil {
+				log.Printf("⚠️ duration probe failed for %s: %v", vidURL, err)
+			} else {
+				contentLength = int64(duration * seekCBRBytesPerSecond)
+			}
+		}
+
+		// startByte is how far into the full (un-seeked) output we're asking
+		// ffmpeg to begin, whether that came from an actual Range header or
+		// just a ?t= query param. 206/Content-Range is only valid in
+		// response to a real Range request (RFC 7233); a ?t=-only request
+		// still gets a complete, if shorter, 200 response.
+		startByte := rangeStartByte
+		if rangeHeader == "" && startSec > 0 {
+			startByte = int64(startSec * seekCBRBytesPerSecond)
+		}
+
+		switch {
+		case rangeHeader != "" && contentLength > startByte:
+			w.Header().Set("Accept-Ranges", "bytes")
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", startByte, contentLength-1, contentLength))
+			w.Header().Set("Content-Length", strconv.FormatInt(contentLength-startByte, 10))
+			w.WriteHeader(http.StatusPartialContent)
+		case seekMode && contentLength > startByte:
+			w.Header().Set("Accept-Ranges", "bytes")
+			w.Header().Set("Content-Length", strconv.FormatInt(contentLength-startByte, 10))
+		default:
+			w.Header().Set("Accept-Ranges", "none")
+			w.Header().Set("Transfer-Encoding", "chunked")
+		}
+
+		// ICY metadata blocks are interleaved into the audio bytes, which
+		// would overrun the exact Content-Length/Content-Range we just
+		// declared for a seeked/ranged response — so ICY only applies to a
+		// plain, full-stream request.
+		var icyWriter *icy.Writer
+		if !seekMode && r.Header.Get("Icy-MetaData") == "1" {
+			icyWriter = newICYWriter(w, ytDlpPath, cookiesPath, vidURL)
+		}
 
 		ctx := r.Context()
 
@@ -94,11 +276,53 @@ func streamHandler(ytDlpPath, ffmpegPath, cookiesPath string) http.HandlerFunc {
 		}
 		ytdlpArgs = append(ytdlpArgs, vidURL)
 
-		ytdlpCmd := exec.CommandContext(ctx, ytDlpPath, ytdlpArgs...)
-		
-		// Build ffmpeg command
-		ffmpegCmd := exec.CommandContext(ctx, ffmpegPath,
-			"-i", "pipe:0",
+		// Connect yt-dlp stdout to ffmpeg stdin, routing through the
+		// source-IP/proxy pool when one is configured so a rate-limited
+		// entry is retried transparently on the next one.
+		var (
+			ytdlpCmd    *exec.Cmd
+			ytdlpStdout io.ReadCloser
+			ytdlpStderr bytes.Buffer
+		)
+		if throttlePool.Len() > 0 {
+			cmd, reader, entry, err := throttlePool.StreamWithRetry(ctx, ytDlpPath, ytdlpArgs)
+			if err != nil {
+				log.Printf("❌ throttle pool exhausted for %s: %v", vidURL, err)
+				http.Error(w, "All source IPs/proxies are currently rate-limited", http.StatusServiceUnavailable)
+				return
+			}
+			log.Printf("🔁 Using pool entry %s for %s", entry.Value, vidURL)
+			ytdlpCmd, ytdlpStdout = cmd, reader
+		} else {
+			ytdlpCmd = exec.CommandContext(ctx, ytDlpPath, ytdlpArgs...)
+			ytdlpCmd.Stderr = &ytdlpStderr
+			stdout, err := ytdlpCmd.StdoutPipe()
+			if err != nil {
+				log.Printf("❌ yt-dlp pipe error: %v", err)
+				http.Error(w, "Setup failed", http.StatusInternalServerError)
+				return
+			}
+			ytdlpStdout = stdout
+			if err := ytdlpCmd.Start(); err != nil {
+				log.Printf("❌ yt-dlp start failed: %v", err)
+				http.Error(w, "yt-dlp failed", http.StatusServiceUnavailable)
+				return
+			}
+		}
+
+		// Build ffmpeg command. A -ss before -i asks for a fast (but
+		// imprecise) seek; the second -ss after -i trims the remainder down
+		// to an accurate seek.
+		ffmpegArgs := make([]string, 0, 12)
+		if startSec > 0 {
+			fastSeek := math.Floor(startSec)
+			ffmpegArgs = append(ffmpegArgs, "-ss", fmt.Sprintf("%.2f", fastSeek))
+			ffmpegArgs = append(ffmpegArgs, "-i", "pipe:0")
+			ffmpegArgs = append(ffmpegArgs, "-ss", fmt.Sprintf("%.2f", startSec-fastSeek))
+		} else {
+			ffmpegArgs = append(ffmpegArgs, "-i", "pipe:0")
+		}
+		ffmpegArgs = append(ffmpegArgs,
 			"-vn",
 			"-f", "mp3",
 			"-ac", "2",
@@ -107,19 +331,10 @@ func streamHandler(ytDlpPath, ffmpegPath, cookiesPath string) http.HandlerFunc {
 			"-loglevel", "warning",
 			"pipe:1",
 		)
-		
-		// Connect yt-dlp stdout to ffmpeg stdin
-		ytdlpStdout, err := ytdlpCmd.StdoutPipe()
-		if err != nil {
-			log.Printf("❌ yt-dlp pipe error: %v", err)
-			http.Error(w, "Setup failed", http.StatusInternalServerError)
-			return
-		}
+		ffmpegCmd := exec.CommandContext(ctx, ffmpegPath, ffmpegArgs...)
 		ffmpegCmd.Stdin = ytdlpStdout
-		
-		// Capture stderr
-		var ytdlpStderr, ffmpegStderr bytes.Buffer
-		ytdlpCmd.Stderr = &ytdlpStderr
+
+		var ffmpegStderr bytes.Buffer
 		ffmpegCmd.Stderr = &ffmpegStderr
 
 		// Get ffmpeg stdout
@@ -130,13 +345,6 @@ func streamHandler(ytDlpPath, ffmpegPath, cookiesPath string) http.HandlerFunc {
 			return
 		}
 
-		// Start yt-dlp
-		if err := ytdlpCmd.Start(); err != nil {
-			log.Printf("❌ yt-dlp start failed: %v", err)
-			http.Error(w, "yt-dlp failed", http.StatusServiceUnavailable)
-			return
-		}
-		
 		// Start ffmpeg
 		if err := ffmpegCmd.Start(); err != nil {
 			ytdlpCmd.Process.Kill()
@@ -147,6 +355,24 @@ func streamHandler(ytDlpPath, ffmpegPath, cookiesPath string) http.HandlerFunc {
 
 		log.Printf("🎵 Streaming: %s", vidURL)
 
+		// Only a full, non-seeked transcode is cached: a mid-track seek
+		// response would otherwise poison the cache entry with a partial
+		// file under the same key as the whole track.
+		var cacheWriter cache.Writer
+		if cacheBackend != nil && !seekMode {
+			cw, err := cacheBackend.PutWriter(cacheKey)
+			if err != nil {
+				log.Printf("⚠️ cache write setup failed for %s: %v", vidURL, err)
+			} else {
+				cacheWriter = cw
+			}
+		}
+		abortCache := func() {
+			if cacheWriter != nil {
+				cacheWriter.Discard()
+			}
+		}
+
 		// Cleanup on context cancel
 		go func() {
 			<-ctx.Done()
@@ -164,15 +390,29 @@ func streamHandler(ytDlpPath, ffmpegPath, cookiesPath string) http.HandlerFunc {
 			n, err := ffmpegStdout.Read(buf)
 			if n > 0 {
 				total += n
-				if _, e := w.Write(buf[:n]); e != nil {
+				var writeErr error
+				if icyWriter != nil {
+					_, writeErr = icyWriter.Write(buf[:n])
+				} else {
+					_, writeErr = w.Write(buf[:n])
+				}
+				if writeErr != nil {
 					log.Printf("Client left after %d bytes", total)
 					ytdlpCmd.Process.Kill()
 					ffmpegCmd.Process.Kill()
+					abortCache()
 					return
 				}
 				if flusher != nil {
 					flusher.Flush()
 				}
+				if cacheWriter != nil {
+					if _, cacheErr := cacheWriter.Write(buf[:n]); cacheErr != nil {
+						log.Printf("⚠️ cache write failed for %s: %v", vidURL, cacheErr)
+						abortCache()
+						cacheWriter = nil
+					}
+				}
 			}
 			if err != nil {
 				if err != io.EOF {
@@ -185,7 +425,7 @@ func streamHandler(ytDlpPath, ffmpegPath, cookiesPath string) http.HandlerFunc {
 		// Wait for processes to finish
 		ffmpegCmd.Wait()
 		ytdlpCmd.Wait()
-		
+
 		// Log any errors
 		if ytdlpStderr.Len() > 0 {
 			log.Printf("yt-dlp stderr: %s", ytdlpStderr.String())
@@ -193,15 +433,62 @@ func streamHandler(ytDlpPath, ffmpegPath, cookiesPath string) http.HandlerFunc {
 		if ffmpegStderr.Len() > 0 {
 			log.Printf("ffmpeg stderr: %s", ffmpegStderr.String())
 		}
-		
+
 		if total == 0 {
 			log.Printf("❌ WARNING: Stream completed but sent 0 bytes!")
+			abortCache()
+		} else if cacheWriter != nil {
+			if err := cacheWriter.Close(); err != nil {
+				log.Printf("⚠️ cache commit failed for %s: %v", vidURL, err)
+			} else {
+				log.Printf("📦 cached %s (%d bytes)", vidURL, total)
+			}
 		}
-		
+
 		log.Printf("✅ Stream done: %s (%d bytes)", vidURL, total)
 	}
 }
 
+// newICYWriter resolves vidURL's track info (via icyTrackCache, falling
+// back to yt-dlp) and sets the ICY response headers, returning a Writer
+// that interleaves metadata blocks into whatever is written to w.
+func newICYWriter(w http.ResponseWriter, ytDlpPath, cookiesPath, vidURL string) *icy.Writer {
+	track, ok := icyTrackCache.Get(vidURL)
+	if !ok {
+		var err error
+		track, err = icy.ResolveTrackInfo(ytDlpPath, cookiesPath, vidURL)
+		if err != nil {
+			log.Printf("⚠️ icy metadata resolve failed for %s: %v", vidURL, err)
+		} else {
+			icyTrackCache.Put(vidURL, track)
+		}
+	}
+	w.Header().Set("icy-metaint", strconv.Itoa(icy.DefaultMetaInt))
+	w.Header().Set("icy-name", "go-yt-radio-server")
+	w.Header().Set("icy-genre", "Various")
+	w.Header().Set("icy-br", "128")
+	return icy.NewWriter(w, icy.DefaultMetaInt, track)
+}
+
+// canonicalVideoID extracts the video ID from a "watch?v=<id>" URL (any
+// other query params, e.g. playlist/share params, are ignored) or a
+// "youtu.be/<id>" short link, so differently-formatted URLs for the same
+// video collapse to the same cache key. Falls back to the raw URL if no ID
+// can be found.
+func canonicalVideoID(vidURL string) string {
+	parsed, err := url.Parse(vidURL)
+	if err != nil {
+		return vidURL
+	}
+	if v := parsed.Query().Get("v"); v != "" {
+		return v
+	}
+	if id := strings.TrimPrefix(parsed.Path, "/"); id != "" && parsed.Hostname() == "youtu.be" {
+		return id
+	}
+	return vidURL
+}
+
 func isYouTubeURL(u string) bool {
 	parsed, err := url.Parse(u)
 	if err != nil {
@@ -215,9 +502,16 @@ func isYouTubeURL(u string) bool {
 }
 
 func main() {
-	host, port, ytDlpPath, ffmpegPath, cookiesPath := getConfig()
+	host, port, ytDlpPath, ffmpegPath, cookiesPath, extractor, hlsIdleTimeout, throttleCooldown := getConfig()
 	addr := net.JoinHostPort(host, port)
 
+	throttlePool := throttle.NewPoolFromEnv(throttleCooldown)
+
+	cacheBackend, err := cache.NewBackendFromEnv()
+	if err != nil {
+		log.Fatalf("❌ cache setup failed: %v", err)
+	}
+
 	http.HandleFunc("/radio/stream", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
@@ -226,7 +520,34 @@ func main() {
 			w.WriteHeader(http.StatusOK)
 			return
 		}
-		streamHandler(ytDlpPath, ffmpegPath, cookiesPath)(w, r)
+		streamHandler(ytDlpPath, ffmpegPath, cookiesPath, extractor, throttlePool, cacheBackend)(w, r)
+	})
+
+	http.HandleFunc("/radio/hls", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		hlsStartHandler(ytDlpPath, ffmpegPath, cookiesPath, throttlePool)(w, r)
+	})
+	http.HandleFunc("/radio/hls/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		hlsHandler()(w, r)
+	})
+	go gcHLSSessions(hlsIdleTimeout)
+
+	http.HandleFunc("/radio/queue", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		queueStreamHandler(ytDlpPath, ffmpegPath, cookiesPath, throttlePool)(w, r)
+	})
+	http.HandleFunc("/radio/queue/next", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		queueNextHandler(w, r)
+	})
+	http.HandleFunc("/radio/queue/skip", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		queueSkipHandler(w, r)
+	})
+	http.HandleFunc("/radio/queue/list", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		queueListHandler(w, r)
 	})
 
 	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -236,18 +557,34 @@ func main() {
 		if cookiesPath != "" {
 			cookieStatus = cookiesPath
 		}
-		w.Write([]byte(`{"status":"ok","yt_dlp":"` + ytDlpPath + `","ffmpeg":"` + ffmpegPath + `","cookies":"` + cookieStatus + `"}`))
+		_ = json.NewEncoder(w).Encode(struct {
+			Status    string                `json:"status"`
+			YtDlp     string                `json:"yt_dlp"`
+			Ffmpeg    string                `json:"ffmpeg"`
+			Cookies   string                `json:"cookies"`
+			Extractor string                `json:"extractor"`
+			Throttle  []throttle.EntryStats `json:"throttle"`
+		}{"ok", ytDlpPath, ffmpegPath, cookieStatus, extractor, throttlePool.Stats()})
 	})
 
 	log.Printf("📻 Radio Server listening on http://%s", addr)
 	log.Printf("⚙️ Using yt-dlp: %s | ffmpeg: %s", ytDlpPath, ffmpegPath)
+	log.Printf("🧩 Extractor: %s", extractor)
 	if cookiesPath != "" {
 		log.Printf("🍪 Using cookies: %s", cookiesPath)
 	} else {
 		log.Printf("🍪 No cookies configured")
 	}
 	log.Printf("🔍 Debug mode: Add ?debug=1 to see available formats")
-	
+	log.Printf("📺 HLS mode: GET /radio/hls?url=... (redirects to /radio/hls/{id}/index.m3u8, idle timeout %s)", hlsIdleTimeout)
+	log.Printf("🔁 Queue mode: POST /radio/queue {\"urls\":[...]} for a continuous back-to-back stream")
+	if n := throttlePool.Len(); n > 0 {
+		log.Printf("🌐 Throttle pool: %d source IP(s)/proxy(ies), cooldown %s", n, throttleCooldown)
+	}
+	if cacheBackend == nil {
+		log.Printf("💾 Transcode cache: disabled (CACHE_BACKEND=none)")
+	}
+
 	if host == "" || host == "0.0.0.0" {
 		if ip := getOutboundIP(); ip != "" {
 			log.Printf("🌐 LAN access: http://%s:%s/radio/stream?url=...", ip, port)
@@ -0,0 +1,38 @@
+// Package cache stores fully-transcoded MP3s so repeat listens of the same
+// video cost zero CPU and zero YouTube bandwidth, behind a pluggable
+// Backend (local disk or S3).
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+)
+
+// Key returns the cache key for a video and its encoding parameters: the
+// SHA-256 of the canonical video ID plus bitrate/sample-rate/channel count,
+// so different encode settings for the same video don't collide.
+func Key(videoID string, bitrateKbps, sampleRateHz, channels int) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%d|%d", videoID, bitrateKbps, sampleRateHz, channels)))
+	return hex.EncodeToString(sum[:])
+}
+
+// Writer is returned by Backend.PutWriter. Close commits the bytes written
+// so far to the cache; Discard abandons them, e.g. when the client
+// disconnects mid-transcode and the cached copy would be truncated.
+type Writer interface {
+	io.Writer
+	Close() error
+	Discard() error
+}
+
+// Backend stores and retrieves finished MP3s by cache key.
+type Backend interface {
+	// Get returns a seekable reader for key and its size, suitable for
+	// http.ServeContent, and whether it was found.
+	Get(key string) (r io.ReadSeekCloser, size int64, ok bool)
+	// PutWriter returns a Writer that stores whatever is written to it
+	// under key once Close is called.
+	PutWriter(key string) (Writer, error)
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// NewBackendFromEnv builds a Backend from CACHE_BACKEND=disk|s3|none
+// (default disk): CACHE_DIR/CACHE_MAX_SIZE_MB configure the disk backend,
+// CACHE_S3_BUCKET/CACHE_S3_PREFIX configure the S3 one. Returns a nil
+// Backend and nil error for "none", disabling caching entirely.
+func NewBackendFromEnv() (Backend, error) {
+	switch getEnv("CACHE_BACKEND", "disk") {
+	case "none":
+		return nil, nil
+
+	case "s3":
+		bucket := getEnv("CACHE_S3_BUCKET", "")
+		if bucket == "" {
+			return nil, fmt.Errorf("CACHE_S3_BUCKET is required for CACHE_BACKEND=s3")
+		}
+		cfg, err := awsconfig.LoadDefaultConfig(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("load AWS config: %w", err)
+		}
+		log.Printf("💾 S3 cache: s3://%s/%s", bucket, getEnv("CACHE_S3_PREFIX", ""))
+		return NewS3Backend(s3.NewFromConfig(cfg), bucket, getEnv("CACHE_S3_PREFIX", "")), nil
+
+	default:
+		dir := getEnv("CACHE_DIR", "./radio-cache")
+		maxMB := 2048
+		if v, err := strconv.Atoi(getEnv("CACHE_MAX_SIZE_MB", "")); err == nil && v > 0 {
+			maxMB = v
+		}
+		backend, err := NewDiskBackend(dir, int64(maxMB)*1024*1024)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("💾 Disk cache: %s (max %d MB)", dir, maxMB)
+		return backend, nil
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,198 @@
+package cache
+
+import (
+	"container/list"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DiskBackend is a local-disk LRU cache of finished MP3 files, bounded by
+// total size on disk rather than entry count.
+type DiskBackend struct {
+	dir     string
+	maxSize int64
+
+	mu      sync.Mutex
+	size    int64
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type diskCacheEntry struct {
+	key  string
+	size int64
+}
+
+// NewDiskBackend creates dir if needed and returns a backend that evicts
+// least-recently-used files once their combined size exceeds maxSize bytes.
+// Any *.mp3 files already in dir from a previous process are rehydrated
+// into the LRU index, so a restart doesn't forget what's on disk and stop
+// enforcing maxSize.
+func NewDiskBackend(dir string, maxSize int64) (*DiskBackend, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("mkdir cache dir: %w", err)
+	}
+	d := &DiskBackend{
+		dir:     dir,
+		maxSize: maxSize,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+	if err := d.loadExisting(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// loadExisting scans dir for already-cached *.mp3 files and indexes them
+// oldest-to-newest by mtime, so the LRU order matches what it would have
+// been had this process been running the whole time. Leftover tmp-*.mp3
+// files from a write that never completed are removed instead of indexed.
+func (d *DiskBackend) loadExisting() error {
+	files, err := os.ReadDir(d.dir)
+	if err != nil {
+		return fmt.Errorf("scan cache dir: %w", err)
+	}
+
+	type found struct {
+		key     string
+		size    int64
+		modTime int64
+	}
+	var existing []found
+	for _, f := range files {
+		name := f.Name()
+		if f.IsDir() || !strings.HasSuffix(name, ".mp3") {
+			continue
+		}
+		if strings.HasPrefix(name, "tmp-") {
+			os.Remove(filepath.Join(d.dir, name))
+			continue
+		}
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+		existing = append(existing, found{
+			key:     strings.TrimSuffix(name, ".mp3"),
+			size:    info.Size(),
+			modTime: info.ModTime().UnixNano(),
+		})
+	}
+	sort.Slice(existing, func(i, j int) bool { return existing[i].modTime < existing[j].modTime })
+
+	for _, e := range existing {
+		el := d.order.PushFront(&diskCacheEntry{key: e.key, size: e.size})
+		d.entries[e.key] = el
+		d.size += e.size
+	}
+	return nil
+}
+
+func (d *DiskBackend) path(key string) string {
+	return filepath.Join(d.dir, key+".mp3")
+}
+
+// Get opens the cached file for key, if present, moving it to the front of
+// the LRU order.
+func (d *DiskBackend) Get(key string) (io.ReadSeekCloser, int64, bool) {
+	d.mu.Lock()
+	el, ok := d.entries[key]
+	if ok {
+		d.order.MoveToFront(el)
+	}
+	d.mu.Unlock()
+	if !ok {
+		return nil, 0, false
+	}
+
+	f, err := os.Open(d.path(key))
+	if err != nil {
+		return nil, 0, false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, 0, false
+	}
+	return f, info.Size(), true
+}
+
+// PutWriter writes to a temp file in dir, atomically renaming it into the
+// cache under key once Close reports success.
+func (d *DiskBackend) PutWriter(key string) (Writer, error) {
+	tmp, err := os.CreateTemp(d.dir, "tmp-*.mp3")
+	if err != nil {
+		return nil, fmt.Errorf("create temp: %w", err)
+	}
+	return &diskWriter{d: d, key: key, tmp: tmp}, nil
+}
+
+type diskWriter struct {
+	d   *DiskBackend
+	key string
+	tmp *os.File
+}
+
+func (w *diskWriter) Write(p []byte) (int, error) {
+	return w.tmp.Write(p)
+}
+
+func (w *diskWriter) Close() error {
+	info, statErr := w.tmp.Stat()
+	if err := w.tmp.Close(); err != nil {
+		os.Remove(w.tmp.Name())
+		return err
+	}
+	if statErr != nil {
+		os.Remove(w.tmp.Name())
+		return statErr
+	}
+
+	final := w.d.path(w.key)
+	if err := os.Rename(w.tmp.Name(), final); err != nil {
+		os.Remove(w.tmp.Name())
+		return fmt.Errorf("atomic move into cache: %w", err)
+	}
+
+	w.d.track(w.key, info.Size())
+	return nil
+}
+
+// Discard abandons a partially-written entry, e.g. after a client
+// disconnects mid-transcode, without promoting it into the cache.
+func (w *diskWriter) Discard() error {
+	w.tmp.Close()
+	return os.Remove(w.tmp.Name())
+}
+
+// track records key's size in the LRU order and evicts the oldest entries
+// until the cache is back under its size budget.
+func (d *DiskBackend) track(key string, size int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if el, ok := d.entries[key]; ok {
+		d.size -= el.Value.(*diskCacheEntry).size
+		el.Value.(*diskCacheEntry).size = size
+		d.order.MoveToFront(el)
+	} else {
+		el := d.order.PushFront(&diskCacheEntry{key: key, size: size})
+		d.entries[key] = el
+	}
+	d.size += size
+
+	for d.size > d.maxSize && d.order.Len() > 0 {
+		oldest := d.order.Back()
+		entry := oldest.Value.(*diskCacheEntry)
+		d.order.Remove(oldest)
+		delete(d.entries, entry.key)
+		d.size -= entry.size
+		os.Remove(d.path(entry.key))
+	}
+}
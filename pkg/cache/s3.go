@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3Backend stores finished MP3 files in an S3 bucket, so the cache
+// survives past a single instance and can be shared across replicas.
+type S3Backend struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+// NewS3Backend wraps an S3 client for bucket, storing objects under prefix
+// (e.g. "radio-cache/").
+func NewS3Backend(client *s3.Client, bucket, prefix string) *S3Backend {
+	return &S3Backend{client: client, bucket: bucket, prefix: prefix}
+}
+
+func (s *S3Backend) objectKey(key string) string {
+	return s.prefix + key + ".mp3"
+}
+
+// Get downloads the cached object for key, if present. The object is
+// buffered in memory so it can be handed back as an io.ReadSeekCloser for
+// http.ServeContent.
+func (s *S3Backend) Get(key string) (io.ReadSeekCloser, int64, bool) {
+	out, err := s.client.GetObject(context.Background(), &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.objectKey(key)),
+	})
+	if err != nil {
+		return nil, 0, false
+	}
+	defer out.Body.Close()
+
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, 0, false
+	}
+	return seekCloser{bytes.NewReader(data)}, int64(len(data)), true
+}
+
+// PutWriter tees the written bytes to a local temp file (like DiskBackend
+// does) and streams that file into S3 on Close via the SDK's multipart
+// manager, so a long transcode never has to hold the whole MP3 in memory.
+func (s *S3Backend) PutWriter(key string) (Writer, error) {
+	tmp, err := os.CreateTemp("", "s3-cache-*.mp3")
+	if err != nil {
+		return nil, fmt.Errorf("create temp: %w", err)
+	}
+	return &s3Writer{backend: s, key: key, tmp: tmp}, nil
+}
+
+type s3Writer struct {
+	backend *S3Backend
+	key     string
+	tmp     *os.File
+}
+
+func (w *s3Writer) Write(p []byte) (int, error) {
+	return w.tmp.Write(p)
+}
+
+func (w *s3Writer) Close() error {
+	defer os.Remove(w.tmp.Name())
+
+	if _, err := w.tmp.Seek(0, io.SeekStart); err != nil {
+		w.tmp.Close()
+		return fmt.Errorf("seek temp file: %w", err)
+	}
+
+	uploader := manager.NewUploader(w.backend.client)
+	_, err := uploader.Upload(context.Background(), &s3.PutObjectInput{
+		Bucket: aws.String(w.backend.bucket),
+		Key:    aws.String(w.backend.objectKey(w.key)),
+		Body:   w.tmp,
+	})
+	w.tmp.Close()
+	if err != nil {
+		return fmt.Errorf("s3 multipart upload: %w", err)
+	}
+	return nil
+}
+
+// Discard abandons the temp file; nothing has been uploaded yet since
+// PutWriter only tees to disk until Close.
+func (w *s3Writer) Discard() error {
+	w.tmp.Close()
+	return os.Remove(w.tmp.Name())
+}
+
+// seekCloser adapts a *bytes.Reader (already a Seeker) to io.ReadSeekCloser
+// for in-memory Get results.
+type seekCloser struct {
+	*bytes.Reader
+}
+
+func (seekCloser) Close() error { return nil }
@@ -0,0 +1,66 @@
+package icy
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Cache is a small size-bounded LRU cache of TrackInfo keyed by video URL,
+// so repeat requests for the same video skip the yt-dlp -J round trip.
+type Cache struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List
+}
+
+type cacheEntry struct {
+	key   string
+	value TrackInfo
+}
+
+// NewCache creates an LRU cache holding up to capacity entries.
+func NewCache(capacity int) *Cache {
+	return &Cache{
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get returns the cached TrackInfo for key, if present.
+func (c *Cache) Get(key string) (TrackInfo, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return TrackInfo{}, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*cacheEntry).value, true
+}
+
+// Put stores value under key, evicting the least-recently-used entry if the
+// cache is already at capacity.
+func (c *Cache) Put(key string, value TrackInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		el.Value.(*cacheEntry).value = value
+		c.order.MoveToFront(el)
+		return
+	}
+
+	el := c.order.PushFront(&cacheEntry{key: key, value: value})
+	c.items[key] = el
+
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.items, oldest.Value.(*cacheEntry).key)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Package icy implements Icecast/SHOUTcast-style ICY metadata injection, so
+// standard shoutcast clients (VLC, WinAmp, car head units) can display a
+// now-playing title for the plain MP3 stream.
+package icy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+)
+
+// DefaultMetaInt is the number of audio bytes between metadata blocks, sent
+// to the client as the icy-metaint response header.
+const DefaultMetaInt = 16000
+
+// TrackInfo is the now-playing metadata for one stream.
+type TrackInfo struct {
+	Artist string
+	Title  string
+}
+
+// StreamTitle formats the metadata the way ICY clients expect it.
+func (t TrackInfo) StreamTitle() string {
+	if t.Artist == "" {
+		return t.Title
+	}
+	return t.Artist + " - " + t.Title
+}
+
+// Writer wraps an io.Writer, splitting every metaInt bytes of audio with an
+// ICY metadata block describing the current track. SetTrack can change the
+// track mid-stream, e.g. on a queue transition — from a different goroutine
+// than the one calling Write, so track is guarded by mu.
+type Writer struct {
+	w       io.Writer
+	metaInt int
+	sent    int
+
+	mu    sync.Mutex
+	track TrackInfo
+}
+
+// NewWriter wraps w so every metaInt bytes of audio are followed by an ICY
+// metadata block, starting with the given track.
+func NewWriter(w io.Writer, metaInt int, track TrackInfo) *Writer {
+	return &Writer{w: w, metaInt: metaInt, track: track}
+}
+
+// SetTrack updates the metadata emitted in the next block.
+func (iw *Writer) SetTrack(track TrackInfo) {
+	iw.mu.Lock()
+	iw.track = track
+	iw.mu.Unlock()
+}
+
+// Write implements io.Writer, interleaving ICY metadata blocks into the
+// audio byte stream every metaInt bytes.
+func (iw *Writer) Write(p []byte) (int, error) {
+	total := 0
+	for len(p) > 0 {
+		remaining := iw.metaInt - iw.sent
+		n := len(p)
+		if n > remaining {
+			n = remaining
+		}
+		if _, err := iw.w.Write(p[:n]); err != nil {
+			return total, err
+		}
+		total += n
+		iw.sent += n
+		p = p[n:]
+
+		if iw.sent == iw.metaInt {
+			if err := iw.writeMetaBlock(); err != nil {
+				return total, err
+			}
+			iw.sent = 0
+		}
+	}
+	return total, nil
+}
+
+// writeMetaBlock emits one ICY metadata frame: a length byte (in 16-byte
+// units) followed by that many bytes of "StreamTitle='...';", NUL-padded.
+func (iw *Writer) writeMetaBlock() error {
+	iw.mu.Lock()
+	track := iw.track
+	iw.mu.Unlock()
+
+	meta := fmt.Sprintf("StreamTitle='%s';", track.StreamTitle())
+	blocks := (len(meta) + 15) / 16
+	padded := make([]byte, blocks*16)
+	copy(padded, meta)
+
+	if _, err := iw.w.Write([]byte{byte(blocks)}); err != nil {
+		return err
+	}
+	_, err := iw.w.Write(padded)
+	return err
+}
+
+// ResolveTrackInfo runs "yt-dlp -J --skip-download" once to pull a video's
+// title and uploader without starting the audio pipeline.
+func ResolveTrackInfo(ytDlpPath, cookiesPath, vidURL string) (TrackInfo, error) {
+	args := []string{"-J", "--skip-download", "--no-warnings"}
+	if cookiesPath != "" {
+		args = append(args, "--cookies", cookiesPath)
+	}
+	args = append(args, vidURL)
+
+	out, err := exec.Command(ytDlpPath, args...).Output()
+	if err != nil {
+		return TrackInfo{}, fmt.Errorf("yt-dlp -J: %w", err)
+	}
+
+	var meta struct {
+		Title    string `json:"title"`
+		Uploader string `json:"uploader"`
+	}
+	if err := json.NewDecoder(bytes.NewReader(out)).Decode(&meta); err != nil {
+		return TrackInfo{}, fmt.Errorf("parse yt-dlp JSON: %w", err)
+	}
+
+	return TrackInfo{Artist: meta.Uploader, Title: meta.Title}, nil
+}
@@ -0,0 +1,269 @@
+// Package queue implements a continuous "radio" playlist: a list of YouTube
+// URLs streamed back-to-back over one long-lived HTTP response so listeners
+// don't disconnect between tracks.
+package queue
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"syscall"
+
+	"github.com/ChamikaSamaraweera/go-yt-radio-server/pkg/throttle"
+)
+
+// Recommender supplies a follow-up track when a queue drains, so a session
+// can keep playing past its initial playlist instead of ending.
+type Recommender interface {
+	Recommend(lastURL string) (nextURL string, ok bool)
+}
+
+// Session is one listener's playlist: a pending list of YouTube URLs fed
+// sequentially, through yt-dlp, into a single long-lived ffmpeg process
+// reading from a named pipe.
+type Session struct {
+	ID string
+
+	ytDlpPath    string
+	ffmpegPath   string
+	cookiesPath  string
+	throttlePool *throttle.Pool
+	recommender  Recommender
+
+	mu      sync.Mutex
+	pending []string
+	current string
+
+	// OnTrackChange, if set, is called with the URL of each track as it
+	// starts playing — e.g. to refresh ICY metadata on transitions.
+	OnTrackChange func(vidURL string)
+
+	skip     chan struct{}
+	done     chan struct{}
+	closeOne sync.Once
+
+	dir       string
+	pipePath  string
+	ffmpegCmd *exec.Cmd
+}
+
+// NewSession creates a queue session over the given URLs (optionally
+// shuffled) and starts its ffmpeg process plus feeder goroutine. The
+// returned reader is ffmpeg's MP3 output and must be copied to the
+// listener's response.
+func NewSession(id, ytDlpPath, ffmpegPath, cookiesPath string, throttlePool *throttle.Pool, urls []string, shuffle bool, rec Recommender, onTrackChange func(vidURL string)) (*Session, io.ReadCloser, error) {
+	queued := append([]string(nil), urls...)
+	if shuffle {
+		rand.Shuffle(len(queued), func(i, j int) { queued[i], queued[j] = queued[j], queued[i] })
+	}
+
+	dir, err := os.MkdirTemp("", "radio-queue-*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("mkdir temp: %w", err)
+	}
+	pipePath := filepath.Join(dir, "audio.pipe")
+	if err := syscall.Mkfifo(pipePath, 0600); err != nil {
+		os.RemoveAll(dir)
+		return nil, nil, fmt.Errorf("mkfifo: %w", err)
+	}
+
+	ffmpegCmd := exec.Command(ffmpegPath,
+		"-hide_banner", "-loglevel", "error",
+		"-re",
+		"-i", pipePath,
+		"-vn",
+		"-f", "mp3",
+		"-ac", "2",
+		"-ar", "44100",
+		"-b:a", "128k",
+		"pipe:1",
+	)
+	ffmpegStdout, err := ffmpegCmd.StdoutPipe()
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, nil, fmt.Errorf("ffmpeg stdout pipe: %w", err)
+	}
+	if err := ffmpegCmd.Start(); err != nil {
+		os.RemoveAll(dir)
+		return nil, nil, fmt.Errorf("ffmpeg start: %w", err)
+	}
+
+	s := &Session{
+		ID:            id,
+		ytDlpPath:     ytDlpPath,
+		ffmpegPath:    ffmpegPath,
+		cookiesPath:   cookiesPath,
+		throttlePool:  throttlePool,
+		recommender:   rec,
+		OnTrackChange: onTrackChange,
+		pending:       queued,
+		skip:          make(chan struct{}, 1),
+		done:          make(chan struct{}),
+		dir:           dir,
+		pipePath:      pipePath,
+		ffmpegCmd:     ffmpegCmd,
+	}
+
+	go s.feed()
+
+	return s, ffmpegStdout, nil
+}
+
+// feed opens the named pipe for writing (blocking until ffmpeg opens it for
+// reading) and then runs yt-dlp for each queued URL in turn, copying its
+// stdout into the pipe so ffmpeg sees one continuous input stream.
+func (s *Session) feed() {
+	pipeWriter, err := os.OpenFile(s.pipePath, os.O_WRONLY, 0)
+	if err != nil {
+		log.Printf("❌ queue %s: open pipe: %v", s.ID, err)
+		s.Close()
+		return
+	}
+	defer pipeWriter.Close()
+
+	for {
+		select {
+		case <-s.done:
+			log.Printf("🛑 queue %s: closed, stopping feed", s.ID)
+			return
+		default:
+		}
+
+		vidURL, ok := s.next()
+		if !ok {
+			break
+		}
+
+		s.mu.Lock()
+		s.current = vidURL
+		s.mu.Unlock()
+
+		if s.OnTrackChange != nil {
+			s.OnTrackChange(vidURL)
+		}
+
+		log.Printf("🎵 queue %s: now playing %s", s.ID, vidURL)
+		if err := s.playOne(vidURL, pipeWriter); err != nil {
+			log.Printf("⚠️ queue %s: %v", s.ID, err)
+		}
+	}
+
+	log.Printf("🛑 queue %s: drained, closing", s.ID)
+	s.Close()
+}
+
+// next pops the next URL to play, consulting the recommender when the
+// pending list is empty instead of ending the session outright.
+func (s *Session) next() (string, bool) {
+	s.mu.Lock()
+	if len(s.pending) > 0 {
+		vidURL := s.pending[0]
+		s.pending = s.pending[1:]
+		s.mu.Unlock()
+		return vidURL, true
+	}
+	last := s.current
+	s.mu.Unlock()
+
+	if s.recommender != nil {
+		if next, ok := s.recommender.Recommend(last); ok {
+			return next, true
+		}
+	}
+	return "", false
+}
+
+// playOne runs yt-dlp for a single URL and copies its audio into w, stopping
+// early if Skip is called. When a throttle pool is configured, it is routed
+// through the same source-IP/proxy rotation and 429 backoff as the plain
+// stream endpoint.
+func (s *Session) playOne(vidURL string, w io.Writer) error {
+	args := []string{"-f", "bestaudio[ext=m4a]/bestaudio", "-o", "-", "--no-warnings", "--quiet"}
+	if s.cookiesPath != "" {
+		args = append(args, "--cookies", s.cookiesPath)
+	}
+	args = append(args, vidURL)
+
+	var (
+		cmd    *exec.Cmd
+		stdout io.ReadCloser
+	)
+	if s.throttlePool.Len() > 0 {
+		c, reader, entry, err := s.throttlePool.StreamWithRetry(context.Background(), s.ytDlpPath, args)
+		if err != nil {
+			return fmt.Errorf("throttle pool exhausted: %w", err)
+		}
+		log.Printf("🔁 queue %s: using pool entry %s for %s", s.ID, entry.Value, vidURL)
+		cmd, stdout = c, reader
+	} else {
+		c := exec.Command(s.ytDlpPath, args...)
+		out, err := c.StdoutPipe()
+		if err != nil {
+			return fmt.Errorf("yt-dlp pipe: %w", err)
+		}
+		if err := c.Start(); err != nil {
+			return fmt.Errorf("yt-dlp start: %w", err)
+		}
+		cmd, stdout = c, out
+	}
+
+	copyDone := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(w, stdout)
+		copyDone <- err
+	}()
+
+	select {
+	case err := <-copyDone:
+		cmd.Wait()
+		return err
+	case <-s.skip:
+		cmd.Process.Kill()
+		<-copyDone
+		cmd.Wait()
+		return nil
+	case <-s.done:
+		cmd.Process.Kill()
+		return nil
+	}
+}
+
+// AddNext appends a URL to the back of the pending queue.
+func (s *Session) AddNext(vidURL string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending = append(s.pending, vidURL)
+}
+
+// Skip stops the currently playing track and advances to the next one.
+func (s *Session) Skip() {
+	select {
+	case s.skip <- struct{}{}:
+	default:
+	}
+}
+
+// List returns the currently playing URL (if any) and the pending queue.
+func (s *Session) List() (current string, pending []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.current, append([]string(nil), s.pending...)
+}
+
+// Close tears down the session's ffmpeg process and temp directory. Safe to
+// call more than once.
+func (s *Session) Close() {
+	s.closeOne.Do(func() {
+		close(s.done)
+		if s.ffmpegCmd.Process != nil {
+			s.ffmpegCmd.Process.Kill()
+		}
+		os.RemoveAll(s.dir)
+	})
+}
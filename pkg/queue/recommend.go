@@ -0,0 +1,68 @@
+package queue
+
+import (
+	"encoding/json"
+	"net/url"
+	"os/exec"
+	"strings"
+)
+
+// YtDlpRecommender recommends a follow-up track using YouTube's own "RD"
+// auto-mix playlist for the last played video, so a session that drains its
+// initial playlist keeps playing related videos instead of ending.
+type YtDlpRecommender struct {
+	YtDlpPath   string
+	CookiesPath string
+}
+
+// Recommend looks up lastURL's video ID and asks yt-dlp for the next entry
+// in its auto-generated "Radio" mix (list=RD<id>), skipping the seed video
+// itself. It returns ok=false if the ID can't be extracted or the mix can't
+// be resolved.
+func (r YtDlpRecommender) Recommend(lastURL string) (nextURL string, ok bool) {
+	id := extractVideoID(lastURL)
+	if id == "" {
+		return "", false
+	}
+	mixURL := "https://www.youtube.com/watch?v=" + id + "&list=RD" + id
+
+	args := []string{"--flat-playlist", "-J", "--no-warnings", "--playlist-end", "2"}
+	if r.CookiesPath != "" {
+		args = append(args, "--cookies", r.CookiesPath)
+	}
+	args = append(args, mixURL)
+
+	out, err := exec.Command(r.YtDlpPath, args...).Output()
+	if err != nil {
+		return "", false
+	}
+
+	var mix struct {
+		Entries []struct {
+			ID string `json:"id"`
+		} `json:"entries"`
+	}
+	if err := json.Unmarshal(out, &mix); err != nil {
+		return "", false
+	}
+
+	for _, e := range mix.Entries {
+		if e.ID != "" && e.ID != id {
+			return "https://www.youtube.com/watch?v=" + e.ID, true
+		}
+	}
+	return "", false
+}
+
+// extractVideoID returns the canonical video ID from either a
+// "watch?v=<id>" URL or a "youtu.be/<id>" short link.
+func extractVideoID(vidURL string) string {
+	parsed, err := url.Parse(vidURL)
+	if err != nil {
+		return ""
+	}
+	if v := parsed.Query().Get("v"); v != "" {
+		return v
+	}
+	return strings.TrimPrefix(parsed.Path, "/")
+}
@@ -0,0 +1,141 @@
+package streamer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/kkdai/youtube/v2"
+)
+
+// StreamFromURLNative resolves and streams audio using the native Go YouTube
+// extractor instead of shelling out to yt-dlp. It picks the best audio-only
+// format, opens its download stream, and pipes that body straight into
+// ffmpeg over pipe:0 — no python subprocess, lower startup latency.
+func StreamFromURLNative(ctx context.Context, w http.ResponseWriter, ytURL string) error {
+	if !isValidYouTubeURL(ytURL) {
+		return fmt.Errorf("invalid YouTube URL: %s", ytURL)
+	}
+
+	client := youtube.Client{}
+
+	video, err := client.GetVideoContext(ctx, ytURL)
+	if err != nil {
+		return fmt.Errorf("native extract: %w", err)
+	}
+
+	format := bestAudioFormat(video.Formats)
+	if format == nil {
+		return fmt.Errorf("no audio-only format available for %s", ytURL)
+	}
+
+	audioStream, _, err := client.GetStreamContext(ctx, video, format)
+	if err != nil {
+		return fmt.Errorf("native stream open: %w", err)
+	}
+	defer audioStream.Close()
+
+	w.Header().Set("Content-Type", "audio/mpeg")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ffmpegCmd := exec.CommandContext(ctx, "ffmpeg",
+		"-hide_banner", "-loglevel", "error",
+		"-i", "pipe:0",
+		"-f", "mp3",
+		"-ac", "2", // stereo
+		"-ar", "44100", // 44.1kHz
+		"-b:a", "128k", // 128kbps
+		"-vn", // no video
+		"pipe:1",
+	)
+	ffmpegCmd.Stdin = audioStream
+
+	ffmpegStdout, err := ffmpegCmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("ffmpeg stdout pipe: %w", err)
+	}
+
+	if err := ffmpegCmd.Start(); err != nil {
+		return fmt.Errorf("ffmpeg start: %w", err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		ffmpegCmd.Process.Kill()
+	}()
+
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := ffmpegStdout.Read(buf)
+		if n > 0 {
+			if _, writeErr := w.Write(buf[:n]); writeErr != nil {
+				return fmt.Errorf("client disconnected")
+			}
+			if f, ok := w.(http.Flusher); ok {
+				f.Flush()
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("ffmpeg read: %w", err)
+		}
+	}
+
+	return ffmpegCmd.Wait()
+}
+
+// bestAudioFormat picks the audio-only format (mime_type starting with
+// "audio/") with the highest bitrate. Among formats of equal bitrate, one
+// that reports AudioChannels is preferred over one that doesn't;
+// AudioSampleRate is not considered.
+func bestAudioFormat(formats youtube.FormatList) *youtube.Format {
+	var best *youtube.Format
+	for i := range formats {
+		f := &formats[i]
+		if !strings.HasPrefix(f.MimeType, "audio/") {
+			continue
+		}
+		if best == nil {
+			best = f
+			continue
+		}
+		if f.Bitrate != best.Bitrate {
+			if f.Bitrate > best.Bitrate {
+				best = f
+			}
+			continue
+		}
+		if best.AudioChannels == 0 && f.AudioChannels != 0 {
+			best = f
+		}
+	}
+	return best
+}
+
+// FormatDebugString lists the formats the native extractor sees for a video,
+// highest bitrate first, so the ?debug=1 path works without shelling out to
+// "yt-dlp --list-formats".
+func FormatDebugString(ctx context.Context, ytURL string) string {
+	client := youtube.Client{}
+	video, err := client.GetVideoContext(ctx, ytURL)
+	if err != nil {
+		return "Failed to list formats (native): " + err.Error()
+	}
+
+	formats := append(youtube.FormatList{}, video.Formats...)
+	sort.Slice(formats, func(i, j int) bool { return formats[i].Bitrate > formats[j].Bitrate })
+
+	var b strings.Builder
+	for _, f := range formats {
+		fmt.Fprintf(&b, "itag=%-4d  %-30s bitrate=%-8d channels=%-2d sample_rate=%s\n",
+			f.ItagNo, f.MimeType, f.Bitrate, f.AudioChannels, f.AudioSampleRate)
+	}
+	return b.String()
+}
@@ -0,0 +1,36 @@
+package throttle
+
+import "io"
+
+// PrefixReader replays a peeked byte slice before falling through to the
+// underlying reader, so a stream can be inspected without losing its head.
+type PrefixReader struct {
+	prefix  []byte
+	peekErr error
+	r       io.ReadCloser
+	drained bool
+}
+
+// NewPrefixReader returns a reader that yields prefix first, then peekErr
+// (if non-nil and not io.EOF-already-consumed), then the rest of r.
+func NewPrefixReader(prefix []byte, peekErr error, r io.ReadCloser) *PrefixReader {
+	return &PrefixReader{prefix: prefix, peekErr: peekErr, r: r}
+}
+
+func (pr *PrefixReader) Read(p []byte) (int, error) {
+	if len(pr.prefix) > 0 {
+		n := copy(p, pr.prefix)
+		pr.prefix = pr.prefix[n:]
+		return n, nil
+	}
+	if pr.peekErr != nil && !pr.drained {
+		pr.drained = true
+		return 0, pr.peekErr
+	}
+	return pr.r.Read(p)
+}
+
+// Close closes the underlying reader.
+func (pr *PrefixReader) Close() error {
+	return pr.r.Close()
+}
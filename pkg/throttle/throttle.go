@@ -0,0 +1,246 @@
+// Package throttle wraps yt-dlp invocations with a rotating pool of source
+// IPs and/or SOCKS proxies, so a single rate-limited egress address doesn't
+// take the whole server down. Entries that trip YouTube's rate limiter are
+// cooled down with exponential backoff and skipped until they recover.
+package throttle
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EntryKind distinguishes a plain source-address bind from a SOCKS proxy.
+type EntryKind int
+
+const (
+	KindSourceIP EntryKind = iota
+	KindProxy
+)
+
+// throttleMarkers are yt-dlp stderr substrings that indicate YouTube is
+// rate-limiting or blocking the entry that was used.
+var throttleMarkers = []string{"HTTP Error 429", "Sign in to confirm"}
+
+// Entry is one source IP or proxy in the rotation, along with its health.
+type Entry struct {
+	Value string
+	Kind  EntryKind
+
+	mu            sync.Mutex
+	lastUsed      time.Time
+	cooldownUntil time.Time
+	failStreak    int
+	successes     int
+	failures      int
+}
+
+// Args returns the yt-dlp CLI flags that route a request through this entry.
+func (e *Entry) Args() []string {
+	if e.Kind == KindProxy {
+		return []string{"--proxy", e.Value}
+	}
+	return []string{"--source-address", e.Value}
+}
+
+// EntryStats is the JSON-friendly snapshot of one entry's health, for /health.
+type EntryStats struct {
+	Value       string `json:"value"`
+	Kind        string `json:"kind"`
+	Successes   int    `json:"successes"`
+	Failures    int    `json:"failures"`
+	CoolingDown bool   `json:"cooling_down"`
+}
+
+// Pool is a rotating set of source IPs / proxies for yt-dlp invocations.
+type Pool struct {
+	mu           sync.Mutex
+	entries      []*Entry
+	baseCooldown time.Duration
+}
+
+// NewPool builds a pool from explicit proxy and source-IP lists.
+func NewPool(proxies, sourceIPs []string, baseCooldown time.Duration) *Pool {
+	entries := make([]*Entry, 0, len(proxies)+len(sourceIPs))
+	for _, p := range proxies {
+		entries = append(entries, &Entry{Value: p, Kind: KindProxy})
+	}
+	for _, ip := range sourceIPs {
+		entries = append(entries, &Entry{Value: ip, Kind: KindSourceIP})
+	}
+	return &Pool{entries: entries, baseCooldown: baseCooldown}
+}
+
+// NewPoolFromEnv reads PROXIES= and SOURCE_IPS= (comma-separated) to build a
+// pool, with cooldownWindow as the base cooldown applied on a first offense.
+func NewPoolFromEnv(cooldownWindow time.Duration) *Pool {
+	return NewPool(splitEnvList("PROXIES"), splitEnvList("SOURCE_IPS"), cooldownWindow)
+}
+
+func splitEnvList(key string) []string {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return nil
+	}
+	var out []string
+	for _, part := range strings.Split(raw, ",") {
+		if v := strings.TrimSpace(part); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Len reports how many entries the pool has configured.
+func (p *Pool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.entries)
+}
+
+// Acquire returns the least-recently-used entry that isn't cooling down.
+func (p *Pool) Acquire() (*Entry, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	var best *Entry
+	var bestLastUsed time.Time
+	for _, e := range p.entries {
+		e.mu.Lock()
+		available := now.After(e.cooldownUntil)
+		lastUsed := e.lastUsed
+		e.mu.Unlock()
+		if !available {
+			continue
+		}
+		if best == nil || lastUsed.Before(bestLastUsed) {
+			best = e
+			bestLastUsed = lastUsed
+		}
+	}
+	if best == nil {
+		return nil, false
+	}
+	best.mu.Lock()
+	best.lastUsed = now
+	best.mu.Unlock()
+	return best, true
+}
+
+// Report records the outcome of using an entry. On failure, if stderr
+// carries a known rate-limit marker, the entry is cooled down for a window
+// that doubles with each consecutive offense.
+func (p *Pool) Report(e *Entry, success bool, stderr string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if success {
+		e.successes++
+		e.failStreak = 0
+		return
+	}
+
+	e.failures++
+	if !isThrottled(stderr) {
+		return
+	}
+
+	e.failStreak++
+	shift := e.failStreak - 1
+	if shift > 6 {
+		shift = 6 // cap backoff growth at 64x the base window
+	}
+	e.cooldownUntil = time.Now().Add(p.baseCooldown * time.Duration(int64(1)<<uint(shift)))
+}
+
+func isThrottled(stderr string) bool {
+	for _, marker := range throttleMarkers {
+		if strings.Contains(stderr, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// Stats returns a snapshot of every entry's health, for the /health endpoint.
+func (p *Pool) Stats() []EntryStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	stats := make([]EntryStats, 0, len(p.entries))
+	for _, e := range p.entries {
+		e.mu.Lock()
+		kind := "source_ip"
+		if e.Kind == KindProxy {
+			kind = "proxy"
+		}
+		stats = append(stats, EntryStats{
+			Value:       e.Value,
+			Kind:        kind,
+			Successes:   e.successes,
+			Failures:    e.failures,
+			CoolingDown: now.Before(e.cooldownUntil),
+		})
+		e.mu.Unlock()
+	}
+	return stats
+}
+
+// StreamWithRetry runs yt-dlp with extraArgs once per pool entry (in LRU
+// order) until one produces output. An entry whose yt-dlp process exits
+// before writing any bytes, with a rate-limit marker in stderr, is cooled
+// down and the next entry is tried transparently.
+func (p *Pool) StreamWithRetry(ctx context.Context, ytDlpPath string, extraArgs []string) (*exec.Cmd, *PrefixReader, *Entry, error) {
+	attempts := p.Len()
+	if attempts == 0 {
+		attempts = 1
+	}
+
+	var lastErr error
+	for i := 0; i < attempts; i++ {
+		entry, ok := p.Acquire()
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("no available source IPs/proxies (all cooling down)")
+		}
+
+		args := append(append([]string{}, entry.Args()...), extraArgs...)
+		cmd := exec.CommandContext(ctx, ytDlpPath, args...)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			p.Report(entry, false, err.Error())
+			lastErr = err
+			continue
+		}
+		if err := cmd.Start(); err != nil {
+			p.Report(entry, false, err.Error())
+			lastErr = err
+			continue
+		}
+
+		// Peek the first chunk so a fast failure (e.g. a 429) is caught
+		// before we commit to this entry and hand its stdout to the caller.
+		peek := make([]byte, 4096)
+		n, readErr := stdout.Read(peek)
+		if n == 0 {
+			cmd.Wait()
+			p.Report(entry, false, stderr.String())
+			lastErr = fmt.Errorf("%s: %s", entry.Value, strings.TrimSpace(stderr.String()))
+			continue
+		}
+
+		p.Report(entry, true, "")
+		return cmd, NewPrefixReader(peek[:n], readErr, stdout), entry, nil
+	}
+
+	return nil, nil, nil, fmt.Errorf("all pool entries exhausted: %w", lastErr)
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/ChamikaSamaraweera/go-yt-radio-server/pkg/icy"
+	"github.com/ChamikaSamaraweera/go-yt-radio-server/pkg/queue"
+	"github.com/ChamikaSamaraweera/go-yt-radio-server/pkg/throttle"
+)
+
+// queueSessions maps session id -> *queue.Session for all active radio
+// queues, one per listener.
+var queueSessions sync.Map
+
+// newQueueSessionID returns a short random hex id for a new queue session.
+func newQueueSessionID() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+type queueCreateRequest struct {
+	URLs    []string `json:"urls"`
+	Shuffle bool     `json:"shuffle"`
+}
+
+// queueStreamHandler handles POST /radio/queue: it builds a queue session
+// from the JSON body and streams the resulting audio back over this same
+// long-lived HTTP response, track after track.
+func queueStreamHandler(ytDlpPath, ffmpegPath, cookiesPath string, throttlePool *throttle.Pool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "POST required", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req queueCreateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.URLs) == 0 {
+			http.Error(w, "Expected JSON body with a non-empty \"urls\" array", http.StatusBadRequest)
+			return
+		}
+		for _, u := range req.URLs {
+			if !isYouTubeURL(u) {
+				http.Error(w, "Only YouTube/YouTube Music URLs allowed: "+u, http.StatusBadRequest)
+				return
+			}
+		}
+
+		id := newQueueSessionID()
+		w.Header().Set("X-Queue-Session", id)
+		w.Header().Set("Content-Type", "audio/mpeg")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("Transfer-Encoding", "chunked")
+
+		var out io.Writer = w
+		var onTrackChange func(string)
+		if r.Header.Get("Icy-MetaData") == "1" {
+			w.Header().Set("icy-metaint", strconv.Itoa(icy.DefaultMetaInt))
+			w.Header().Set("icy-name", "go-yt-radio-server")
+			w.Header().Set("icy-genre", "Various")
+			w.Header().Set("icy-br", "128")
+			icyWriter := icy.NewWriter(w, icy.DefaultMetaInt, icy.TrackInfo{})
+			out = icyWriter
+			onTrackChange = func(vidURL string) {
+				track, ok := icyTrackCache.Get(vidURL)
+				if !ok {
+					var err error
+					track, err = icy.ResolveTrackInfo(ytDlpPath, cookiesPath, vidURL)
+					if err != nil {
+						log.Printf("⚠️ icy metadata resolve failed for %s: %v", vidURL, err)
+						return
+					}
+					icyTrackCache.Put(vidURL, track)
+				}
+				icyWriter.SetTrack(track)
+			}
+		}
+
+		rec := queue.YtDlpRecommender{YtDlpPath: ytDlpPath, CookiesPath: cookiesPath}
+		sess, audio, err := queue.NewSession(id, ytDlpPath, ffmpegPath, cookiesPath, throttlePool, req.URLs, req.Shuffle, rec, onTrackChange)
+		if err != nil {
+			log.Printf("❌ queue session start failed: %v", err)
+			http.Error(w, "Failed to start queue", http.StatusInternalServerError)
+			return
+		}
+		queueSessions.Store(id, sess)
+		defer func() {
+			queueSessions.Delete(id)
+			sess.Close()
+		}()
+
+		log.Printf("📻 queue %s: started with %d track(s)", id, len(req.URLs))
+
+		go func() {
+			<-r.Context().Done()
+			sess.Close()
+		}()
+
+		buf := make([]byte, 64*1024)
+		flusher, _ := w.(http.Flusher)
+		for {
+			n, readErr := audio.Read(buf)
+			if n > 0 {
+				if _, writeErr := out.Write(buf[:n]); writeErr != nil {
+					log.Printf("queue %s: client left", id)
+					return
+				}
+				if flusher != nil {
+					flusher.Flush()
+				}
+			}
+			if readErr != nil {
+				return
+			}
+		}
+	}
+}
+
+func loadQueueSession(w http.ResponseWriter, r *http.Request) (*queue.Session, bool) {
+	id := r.URL.Query().Get("session")
+	if id == "" {
+		http.Error(w, "Missing ?session=...", http.StatusBadRequest)
+		return nil, false
+	}
+	value, ok := queueSessions.Load(id)
+	if !ok {
+		http.Error(w, "Unknown or finished queue session", http.StatusNotFound)
+		return nil, false
+	}
+	return value.(*queue.Session), true
+}
+
+// queueNextHandler handles POST /radio/queue/next?session=ID, appending a
+// URL to the back of that session's pending queue.
+func queueNextHandler(w http.ResponseWriter, r *http.Request) {
+	sess, ok := loadQueueSession(w, r)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		URL string `json:"url"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || !isYouTubeURL(body.URL) {
+		http.Error(w, "Expected JSON body with a YouTube \"url\"", http.StatusBadRequest)
+		return
+	}
+
+	sess.AddNext(body.URL)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// queueSkipHandler handles POST /radio/queue/skip?session=ID, stopping the
+// currently playing track and advancing to the next one.
+func queueSkipHandler(w http.ResponseWriter, r *http.Request) {
+	sess, ok := loadQueueSession(w, r)
+	if !ok {
+		return
+	}
+	sess.Skip()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// queueListHandler handles GET /radio/queue/list?session=ID, reporting the
+// currently playing URL and the pending queue behind it.
+func queueListHandler(w http.ResponseWriter, r *http.Request) {
+	sess, ok := loadQueueSession(w, r)
+	if !ok {
+		return
+	}
+	current, pending := sess.List()
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(struct {
+		Current string   `json:"current"`
+		Pending []string `json:"pending"`
+	}{current, pending})
+}